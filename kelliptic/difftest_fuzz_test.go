@@ -0,0 +1,168 @@
+// Copyright 2011 ThePiachu. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build secp256k1_difftest
+
+package kelliptic
+
+// This file implements a differential fuzzer for S256 against
+// github.com/decred/dcrd/dcrec/secp256k1/v4, used as an independent oracle
+// for the curve arithmetic in this package.
+//
+// It is gated behind the secp256k1_difftest build tag because it pulls in
+// an extra module dependency that ordinary users of this package shouldn't
+// need: run it with
+//
+//	go test -tags secp256k1_difftest -fuzz FuzzAgainstDcrd ./kelliptic
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	dcrec "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func seedDifftestCorpus(f *testing.F) {
+	seeds := [][]byte{
+		{},
+		{0x00},
+		{0x01},
+		S256().N.Bytes(),
+		new(big.Int).Sub(S256().N, big.NewInt(1)).Bytes(),
+	}
+	for _, s := range seeds {
+		f.Add(s, s)
+	}
+}
+
+// dcrdPoint converts an (x, y) pair to dcrd's jacobian point representation
+// in affine form, for use as the input to dcrd's point operations.
+func dcrdPoint(x, y *big.Int) *dcrec.JacobianPoint {
+	p := new(dcrec.JacobianPoint)
+	p.X.SetByteSlice(leftPad(x.Bytes(), 32))
+	p.Y.SetByteSlice(leftPad(y.Bytes(), 32))
+	p.Z.SetInt(1)
+	return p
+}
+
+func dumpMismatch(t *testing.T, op string, gotX, gotY, wantX, wantY *big.Int) {
+	t.Fatalf("%s mismatch:\n  got  x=%x y=%x\n  want x=%x y=%x", op, gotX, gotY, wantX, wantY)
+}
+
+// FuzzAgainstDcrd cross-checks Add, Double, ScalarMult and ScalarBaseMult on
+// S256 against dcrd's secp256k1 implementation. It also checks that
+// CompressPoint/DecompressPoint round-trip and that Sqrt of a square computed
+// by dcrd squares back to it.
+func FuzzAgainstDcrd(f *testing.F) {
+	seedDifftestCorpus(f)
+
+	curve := S256()
+
+	f.Fuzz(func(t *testing.T, scalarBytes, pointSeedBytes []byte) {
+		k := new(big.Int).SetBytes(scalarBytes)
+		k.Mod(k, curve.N)
+
+		// Derive a point deterministically from pointSeedBytes via
+		// ScalarBaseMult so every fuzz input yields an on-curve point,
+		// including the point at infinity when the seed is empty or zero.
+		px, py := curve.ScalarBaseMult(pointSeedBytes)
+		if px == nil {
+			px, py = big.NewInt(0), big.NewInt(0)
+		}
+
+		// ScalarMult / ScalarBaseMult against dcrd.
+		var dcrdPriv dcrec.ModNScalar
+		dcrdPriv.SetByteSlice(leftPad(k.Bytes(), 32))
+
+		var wantBase dcrec.JacobianPoint
+		dcrec.ScalarBaseMultNonConst(&dcrdPriv, &wantBase)
+		wantBase.ToAffine()
+
+		gotBaseX, gotBaseY := curve.ScalarBaseMult(k.Bytes())
+		if gotBaseX == nil {
+			gotBaseX, gotBaseY = big.NewInt(0), big.NewInt(0)
+		}
+		if !fieldValMatches(&wantBase.X, gotBaseX) || !fieldValMatches(&wantBase.Y, gotBaseY) {
+			dumpMismatch(t, "ScalarBaseMult", gotBaseX, gotBaseY, fieldValToInt(&wantBase.X), fieldValToInt(&wantBase.Y))
+		}
+
+		if px.Sign() == 0 && py.Sign() == 0 {
+			return // remaining checks need a non-infinity P
+		}
+
+		dp := dcrdPoint(px, py)
+		dp.ToAffine()
+
+		var wantMult dcrec.JacobianPoint
+		dcrec.ScalarMultNonConst(&dcrdPriv, dp, &wantMult)
+		wantMult.ToAffine()
+
+		gotMultX, gotMultY := curve.ScalarMult(px, py, k.Bytes())
+		if gotMultX == nil {
+			gotMultX, gotMultY = big.NewInt(0), big.NewInt(0)
+		}
+		if !fieldValMatches(&wantMult.X, gotMultX) || !fieldValMatches(&wantMult.Y, gotMultY) {
+			dumpMismatch(t, "ScalarMult", gotMultX, gotMultY, fieldValToInt(&wantMult.X), fieldValToInt(&wantMult.Y))
+		}
+
+		// Add and Double.
+		var wantDouble dcrec.JacobianPoint
+		dcrec.DoubleNonConst(dp, &wantDouble)
+		wantDouble.ToAffine()
+
+		gotDoubleX, gotDoubleY := curve.Double(px, py)
+		if !fieldValMatches(&wantDouble.X, gotDoubleX) || !fieldValMatches(&wantDouble.Y, gotDoubleY) {
+			dumpMismatch(t, "Double", gotDoubleX, gotDoubleY, fieldValToInt(&wantDouble.X), fieldValToInt(&wantDouble.Y))
+		}
+
+		var wantAdd dcrec.JacobianPoint
+		dcrec.AddNonConst(dp, dp, &wantAdd)
+		wantAdd.ToAffine()
+
+		gotAddX, gotAddY := curve.Add(px, py, px, py)
+		if !fieldValMatches(&wantAdd.X, gotAddX) || !fieldValMatches(&wantAdd.Y, gotAddY) {
+			dumpMismatch(t, "Add(P,P)", gotAddX, gotAddY, fieldValToInt(&wantAdd.X), fieldValToInt(&wantAdd.Y))
+		}
+
+		// P + (-P) must be the point at infinity.
+		negY := new(big.Int).Sub(curve.P, py)
+		infX, infY := curve.Add(px, py, px, negY)
+		if infX.Sign() != 0 || infY.Sign() != 0 {
+			t.Fatalf("Add(P,-P) did not return the point at infinity: x=%x y=%x", infX, infY)
+		}
+
+		// CompressPoint / DecompressPoint round-trip.
+		cp := curve.CompressPoint(px, py)
+		dx, dy, err := curve.DecompressPoint(cp)
+		if err != nil {
+			t.Fatalf("DecompressPoint: %v", err)
+		}
+		if dx.Cmp(px) != 0 || dy.Cmp(py) != 0 {
+			dumpMismatch(t, "CompressPoint/DecompressPoint", dx, dy, px, py)
+		}
+
+		// Sqrt of x² (squared by dcrd's FieldVal) must square back to x².
+		var fv dcrec.FieldVal
+		fv.SetByteSlice(leftPad(px.Bytes(), 32))
+		var fvSq dcrec.FieldVal
+		fvSq.SquareVal(&fv)
+		gotSqrt := curve.Sqrt(fieldValToInt(&fvSq))
+		gotSqrtSq := new(big.Int).Mul(gotSqrt, gotSqrt)
+		gotSqrtSq.Mod(gotSqrtSq, curve.P)
+		if gotSqrtSq.Cmp(fieldValToInt(&fvSq)) != 0 {
+			t.Fatalf("Sqrt: got %x, which does not square back to %x", gotSqrt, fieldValToInt(&fvSq))
+		}
+	})
+}
+
+func fieldValToInt(fv *dcrec.FieldVal) *big.Int {
+	var b [32]byte
+	fv.Normalize().PutBytesUnchecked(b[:])
+	return new(big.Int).SetBytes(b[:])
+}
+
+func fieldValMatches(fv *dcrec.FieldVal, v *big.Int) bool {
+	return bytes.Equal(leftPad(v.Bytes(), 32), func() []byte { var b [32]byte; fv.PutBytesUnchecked(b[:]); return b[:] }())
+}
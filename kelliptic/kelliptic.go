@@ -16,6 +16,7 @@ package kelliptic
 
 import (
 	"crypto/elliptic"
+	"crypto/subtle"
 	"errors"
 	"math/big"
 	"sync"
@@ -29,6 +30,15 @@ type Curve struct {
 	B       *big.Int // the constant of the Curve equation
 	Gx, Gy  *big.Int // (x,y) of the base point
 	BitSize int      // the size of the underlying field
+
+	baseOnce  sync.Once
+	baseTable [][]point // precomputed windows for ScalarBaseMult, see buildBaseTable
+}
+
+// point is an affine point used by the precomputed base-point table. (0,0)
+// represents the point at infinity, consistent with addJacobian.
+type point struct {
+	x, y *big.Int
 }
 
 func (curve *Curve) Params() *elliptic.CurveParams {
@@ -62,6 +72,9 @@ func (curve *Curve) IsOnCurve(x, y *big.Int) bool {
 //
 // TODO(x): double check if the function is okay
 func (curve *Curve) affineFromJacobian(x, y, z *big.Int) (xOut, yOut *big.Int) {
+	if z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
 	zinv := new(big.Int).ModInverse(z, curve.P)
 	zinvsq := new(big.Int).Mul(zinv, zinv)
 
@@ -81,7 +94,20 @@ func (curve *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
 
 // addJacobian takes two points in Jacobian coordinates, (x1, y1, z1) and
 // (x2, y2, z2) and returns their sum, also in Jacobian form.
+//
+// (0, 0) is treated as the point at infinity, since it can never occur as an
+// actual point on a curve of the form y² = x³ + b with b != 0. Adding the
+// point at infinity to a point returns a fresh copy of that point, and
+// adding a point to itself (u1==u2 and s1==s2 below) is delegated to
+// doubleJacobian.
 func (curve *Curve) addJacobian(x1, y1, z1, x2, y2, z2 *big.Int) (*big.Int, *big.Int, *big.Int) {
+	if x1.Sign() == 0 && y1.Sign() == 0 {
+		return new(big.Int).Set(x2), new(big.Int).Set(y2), new(big.Int).Set(z2)
+	}
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		return new(big.Int).Set(x1), new(big.Int).Set(y1), new(big.Int).Set(z1)
+	}
+
 	// See http://hyperellipticurve.org/EFD/g1p/auto-shortw-jacobian-0.html#addition-add-2007-bl
 	z1z1 := new(big.Int).Mul(z1, z1)
 	z1z1.Mod(z1z1, curve.P)
@@ -92,6 +118,18 @@ func (curve *Curve) addJacobian(x1, y1, z1, x2, y2, z2 *big.Int) (*big.Int, *big
 	u1.Mod(u1, curve.P)
 	u2 := new(big.Int).Mul(x2, z1z1)
 	u2.Mod(u2, curve.P)
+
+	s1 := new(big.Int).Mul(y1, z2)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, curve.P)
+	s2 := new(big.Int).Mul(y2, z1)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, curve.P)
+
+	if u1.Cmp(u2) == 0 && s1.Cmp(s2) == 0 {
+		return curve.doubleJacobian(x1, y1, z1)
+	}
+
 	h := new(big.Int).Sub(u2, u1)
 	if h.Sign() == -1 {
 		h.Add(h, curve.P)
@@ -100,12 +138,6 @@ func (curve *Curve) addJacobian(x1, y1, z1, x2, y2, z2 *big.Int) (*big.Int, *big
 	i.Mul(i, i)
 	j := new(big.Int).Mul(h, i)
 
-	s1 := new(big.Int).Mul(y1, z2)
-	s1.Mul(s1, z2z2)
-	s1.Mod(s1, curve.P)
-	s2 := new(big.Int).Mul(y2, z1)
-	s2.Mul(s2, z1z1)
-	s2.Mod(s2, curve.P)
 	r := new(big.Int).Sub(s2, s1)
 	if r.Sign() == -1 {
 		r.Add(r, curve.P)
@@ -188,6 +220,10 @@ func (curve *Curve) doubleJacobian(x, y, z *big.Int) (*big.Int, *big.Int, *big.I
 //
 // TODO(x): double check if it is okay
 func (curve *Curve) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	if curve == secp256k1 {
+		return curve.scalarMultGLV(Bx, By, k)
+	}
+
 	// We have a slight problem in that the identity of the group (the
 	// point at infinity) cannot be represented in (x, y) form on a finite
 	// machine. Thus the standard add/double algorithm has to be tweaked
@@ -225,10 +261,406 @@ func (curve *Curve) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
 	return curve.affineFromJacobian(x, y, z)
 }
 
+// ScalarMultConstTime returns k*(Bx,By) where k is a number in big-endian
+// form. Unlike ScalarMult, it does not branch on the bits of k: it always
+// performs exactly curve.BitSize double-and-add steps, each one doing the
+// same Jacobian field arithmetic regardless of the bit or of whether R0 is
+// still the point at infinity, and selects the kept result with cselect
+// rather than branching. Callers performing ECDSA/ECDH operations on secret
+// scalars should use this instead of ScalarMult.
+//
+// The ladder maintains the invariant R1 = R0 + (Bx,By) throughout, operating
+// on Jacobian (x,y,z) triples so that the only ModInverse happens once, in
+// affineFromJacobian, after the loop rather than once per bit. The math/big
+// operations it is built on are not constant-time: ModInverse, and the field
+// arithmetic itself, take time that depends on the operand values (for
+// instance, arithmetic on the zero coordinates of R0 while it is still the
+// point at infinity is cheaper).
+func (curve *Curve) ScalarMultConstTime(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	r0x, r0y, r0z := big.NewInt(0), big.NewInt(0), big.NewInt(1)
+	r1x, r1y, r1z := new(big.Int).Set(Bx), new(big.Int).Set(By), big.NewInt(1)
+
+	kInt := new(big.Int).SetBytes(k)
+
+	for i := curve.BitSize - 1; i >= 0; i-- {
+		bit := kInt.Bit(i)
+
+		addX, addY, addZ := curve.addJacobianConstTime(r0x, r0y, r0z, r1x, r1y, r1z)
+		dbl0X, dbl0Y, dbl0Z := curve.doubleJacobian(r0x, r0y, r0z)
+		dbl1X, dbl1Y, dbl1Z := curve.doubleJacobian(r1x, r1y, r1z)
+
+		r0x, r0y, r0z = cselect(bit, dbl0X, addX), cselect(bit, dbl0Y, addY), cselect(bit, dbl0Z, addZ)
+		r1x, r1y, r1z = cselect(bit, addX, dbl1X), cselect(bit, addY, dbl1Y), cselect(bit, addZ, dbl1Z)
+	}
+
+	return curve.affineFromJacobian(r0x, r0y, r0z)
+}
+
+// addJacobianConstTime is addJacobian's constant-time counterpart: it always
+// computes the general addition formula, the doubling formula, and the two
+// operand-copy results, then picks the one that actually applies with
+// cselect, so that the same formulas are computed whether or not either
+// input is the point at infinity or the two inputs coincide. It is only needed by the
+// ladders in ScalarMultConstTime and ScalarBaseMult; ordinary callers should
+// keep using the cheaper, branching addJacobian.
+func (curve *Curve) addJacobianConstTime(x1, y1, z1, x2, y2, z2 *big.Int) (*big.Int, *big.Int, *big.Int) {
+	addX, addY, addZ, selfAdd := curve.addJacobianRaw(x1, y1, z1, x2, y2, z2)
+	dblX, dblY, dblZ := curve.doubleJacobian(x1, y1, z1)
+
+	inf1 := uint(0)
+	if x1.Sign() == 0 && y1.Sign() == 0 {
+		inf1 = 1
+	}
+	inf2 := uint(0)
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		inf2 = 1
+	}
+
+	rx, ry, rz := cselect(selfAdd, addX, dblX), cselect(selfAdd, addY, dblY), cselect(selfAdd, addZ, dblZ)
+	rx, ry, rz = cselect(inf2, rx, x1), cselect(inf2, ry, y1), cselect(inf2, rz, z1)
+	rx, ry, rz = cselect(inf1, rx, x2), cselect(inf1, ry, y2), cselect(inf1, rz, z2)
+	return rx, ry, rz
+}
+
+// addJacobianRaw computes addJacobian's add-2007-bl formula unconditionally,
+// without the point-at-infinity or self-addition special cases, and reports
+// whether u1==u2 and s1==s2 (in which case the result is meaningless and the
+// caller must use doubleJacobian(x1,y1,z1) instead). Its output is also
+// meaningless, by construction, whenever x1/y1 or x2/y2 is the (0,0)
+// infinity sentinel; addJacobianConstTime overrides those cases too.
+func (curve *Curve) addJacobianRaw(x1, y1, z1, x2, y2, z2 *big.Int) (x3, y3, z3 *big.Int, selfAdd uint) {
+	// See http://hyperellipticurve.org/EFD/g1p/auto-shortw-jacobian-0.html#addition-add-2007-bl
+	z1z1 := new(big.Int).Mul(z1, z1)
+	z1z1.Mod(z1z1, curve.P)
+	z2z2 := new(big.Int).Mul(z2, z2)
+	z2z2.Mod(z2z2, curve.P)
+
+	u1 := new(big.Int).Mul(x1, z2z2)
+	u1.Mod(u1, curve.P)
+	u2 := new(big.Int).Mul(x2, z1z1)
+	u2.Mod(u2, curve.P)
+
+	s1 := new(big.Int).Mul(y1, z2)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, curve.P)
+	s2 := new(big.Int).Mul(y2, z1)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, curve.P)
+
+	if u1.Cmp(u2) == 0 && s1.Cmp(s2) == 0 {
+		selfAdd = 1
+	}
+
+	h := new(big.Int).Sub(u2, u1)
+	if h.Sign() == -1 {
+		h.Add(h, curve.P)
+	}
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	j := new(big.Int).Mul(h, i)
+
+	r := new(big.Int).Sub(s2, s1)
+	if r.Sign() == -1 {
+		r.Add(r, curve.P)
+	}
+	r.Lsh(r, 1)
+	v := new(big.Int).Mul(u1, i)
+
+	x3 = new(big.Int).Set(r)
+	x3.Mul(x3, x3)
+	x3.Sub(x3, j)
+	x3.Sub(x3, v)
+	x3.Sub(x3, v)
+	x3.Mod(x3, curve.P)
+
+	y3 = new(big.Int).Set(r)
+	v.Sub(v, x3)
+	y3.Mul(y3, v)
+	s1.Mul(s1, j)
+	s1.Lsh(s1, 1)
+	y3.Sub(y3, s1)
+	y3.Mod(y3, curve.P)
+
+	z3 = new(big.Int).Add(z1, z2)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	if z3.Sign() == -1 {
+		z3.Add(z3, curve.P)
+	}
+	z3.Sub(z3, z2z2)
+	if z3.Sign() == -1 {
+		z3.Add(z3, curve.P)
+	}
+	z3.Mul(z3, h)
+	z3.Mod(z3, curve.P)
+
+	return x3, y3, z3, selfAdd
+}
+
+// doubleOrInfinity returns Double(x,y), except that it leaves the point at
+// infinity ((0,0)) unchanged instead of feeding it through doubleJacobian,
+// which assumes its input actually lies on the curve.
+func (curve *Curve) doubleOrInfinity(x, y *big.Int) (*big.Int, *big.Int) {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return big.NewInt(0), big.NewInt(0)
+	}
+	return curve.Double(x, y)
+}
+
+// cselect returns b if bit == 1 and a if bit == 0, computed arithmetically
+// (a + bit*(b-a)) rather than with a conditional branch on bit, so that it
+// is safe to use on secret-dependent bits.
+func cselect(bit uint, a, b *big.Int) *big.Int {
+	diff := new(big.Int).Sub(b, a)
+	diff.Mul(diff, big.NewInt(int64(bit)))
+	return diff.Add(diff, a)
+}
+
+// baseWindowWidth is the window size, in bits, used by the precomputed
+// base-point table built by buildBaseTable.
+const baseWindowWidth = 4
+
+// buildBaseTable precomputes, for each of the ceil(BitSize/w) windows of
+// width w = baseWindowWidth, the 2^w multiples i·2^(w·window)·G. It is run
+// once per curve, under baseOnce, the first time ScalarBaseMult is called.
+func (curve *Curve) buildBaseTable() {
+	windows := (curve.BitSize + baseWindowWidth - 1) / baseWindowWidth
+	table := make([][]point, windows)
+
+	shiftX, shiftY := curve.Gx, curve.Gy
+	for win := 0; win < windows; win++ {
+		entries := make([]point, 1<<baseWindowWidth)
+		entries[0] = point{big.NewInt(0), big.NewInt(0)} // point at infinity
+		entries[1] = point{new(big.Int).Set(shiftX), new(big.Int).Set(shiftY)}
+		for i := 2; i < len(entries); i++ {
+			ex, ey := curve.Add(entries[i-1].x, entries[i-1].y, shiftX, shiftY)
+			entries[i] = point{ex, ey}
+		}
+		table[win] = entries
+
+		for i := 0; i < baseWindowWidth; i++ {
+			shiftX, shiftY = curve.Double(shiftX, shiftY)
+		}
+	}
+
+	curve.baseTable = table
+}
+
+// selectPoint returns table[idx] without branching on idx: it scans every
+// entry and uses crypto/subtle's constant-time integer comparison together
+// with cselect to pick out the match, so that base-point ECDSA signing
+// doesn't leak which table entries (and hence which nonce bits) were used.
+func selectPoint(table []point, idx int) (*big.Int, *big.Int) {
+	x, y := big.NewInt(0), big.NewInt(0)
+	for i, e := range table {
+		mask := uint(subtle.ConstantTimeEq(int32(i), int32(idx)))
+		x = cselect(mask, x, e.x)
+		y = cselect(mask, y, e.y)
+	}
+	return x, y
+}
+
 // ScalarBaseMult returns k*G, where G is the base point of the group and k is
-// an integer in big-endian form.
+// an integer in big-endian form. It walks k in baseWindowWidth-bit windows
+// against a table of i·2^(w·window)·G precomputed under sync.Once, doing one
+// constant-time table lookup and point addition per window instead of
+// recomputing every double and add from scratch. Folding each window's entry
+// into the accumulator uses addJacobianConstTime rather than addJacobian, so
+// that a window computes the same formulas whether or not the accumulator is
+// still the point at infinity or happens to coincide with the selected
+// entry, instead of branching on the selected nonce bits. The
+// accumulator is carried in Jacobian coordinates so that only the final
+// result pays for a ModInverse, rather than every window.
 func (curve *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
-	return curve.ScalarMult(curve.Gx, curve.Gy, k)
+	curve.baseOnce.Do(curve.buildBaseTable)
+
+	kInt := new(big.Int).SetBytes(k)
+	if kInt.Sign() == 0 {
+		return nil, nil
+	}
+
+	x, y, z := big.NewInt(0), big.NewInt(0), big.NewInt(1)
+	for win, entries := range curve.baseTable {
+		idx := 0
+		for b := 0; b < baseWindowWidth; b++ {
+			idx |= int(kInt.Bit(win*baseWindowWidth+b)) << uint(b)
+		}
+		ex, ey := selectPoint(entries, idx)
+		x, y, z = curve.addJacobianConstTime(x, y, z, ex, ey, big.NewInt(1))
+	}
+	return curve.affineFromJacobian(x, y, z)
+}
+
+// GLV endomorphism acceleration for secp256k1.
+//
+// secp256k1 has an efficiently computable endomorphism φ(x, y) = (β·x mod p,
+// y), where β is a primitive cube root of unity mod p. φ satisfies
+// φ(P) = λ·P for every point P on the curve, where λ is the corresponding
+// primitive cube root of unity mod n. This lets any scalar k be decomposed
+// into (k1, k2), each roughly half the bit length of k, such that
+// k = k1 + k2·λ (mod n) and therefore k·P = k1·P + k2·φ(P), which is then
+// evaluated with a single interleaved double-and-add instead of one long
+// double-and-add over the full-width k.
+//
+// The constants below are a short GLV decomposition basis for secp256k1.
+// β and λ are the squares of the cube roots libsecp256k1 uses (the other
+// root of each pair), and {(a1,b1), (a2,b2)} is a basis of the lattice of
+// (a, b) with a + b·λ ≡ 0 (mod n) for this λ.
+var (
+	secp256k1Beta   *big.Int
+	secp256k1Lambda *big.Int
+	secp256k1A1     *big.Int
+	secp256k1B1     *big.Int
+	secp256k1A2     *big.Int
+	secp256k1B2     *big.Int
+)
+
+func initS256GLV() {
+	secp256k1Beta, _ = new(big.Int).SetString("851695D49A83F8EF919BB86153CBCB16630FB68AED0A766A3EC693D68E6AFA40", 16)
+	secp256k1Lambda, _ = new(big.Int).SetString("AC9C52B33FA3CF1F5AD9E3FD77ED9BA4A880B9FC8EC739C2E0CFC810B51283CE", 16)
+	secp256k1A1, _ = new(big.Int).SetString("4A5D84C4FAD1D149815130F31C84462E4", 16)
+	secp256k1B1, _ = new(big.Int).SetString("2228364F61BCD8F0CDA23C16C0AC386F", 16)
+	secp256k1A2, _ = new(big.Int).SetString("E4437ED6010E88286F547FA90ABFE4C3", 16)
+	secp256k1B2, _ = new(big.Int).SetString("-3086D221A7D46BCDE86C90E49284EB15", 16)
+}
+
+// divRound divides a by positive b, rounding to the nearest integer (ties
+// rounded away from zero), which is what the GLV scalar decomposition needs
+// in place of truncating division. a may be negative; b must be positive.
+func divRound(a, b *big.Int) *big.Int {
+	q, r := new(big.Int), new(big.Int)
+	q.QuoRem(a, b, r)
+	r.Abs(r)
+	r.Lsh(r, 1)
+	if r.Cmp(b) >= 0 {
+		if a.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// splitK decomposes k (already reduced mod N) into k1, k2 such that
+// k == k1 + k2*λ (mod N), with |k1| and |k2| each roughly half the bit
+// length of N, following the standard GLV rounded-lattice-basis method: the
+// target vector (k, 0) is expressed in the basis {(a1,b1), (a2,b2)} of the
+// sublattice orthogonal to (1, λ) mod N, via Babai rounding, and the
+// remainder is the short decomposition.
+func (curve *Curve) splitK(k *big.Int) (k1, k2 *big.Int, k1Neg, k2Neg bool) {
+	c1 := new(big.Int).Neg(divRound(new(big.Int).Mul(k, secp256k1B2), curve.N))
+	c2 := new(big.Int).Neg(divRound(new(big.Int).Mul(k, new(big.Int).Neg(secp256k1B1)), curve.N))
+
+	k1 = new(big.Int).Mul(c1, secp256k1A1)
+	k1.Sub(k, k1)
+	k1.Sub(k1, new(big.Int).Mul(c2, secp256k1A2))
+
+	k2 = new(big.Int).Mul(c1, secp256k1B1)
+	k2.Add(k2, new(big.Int).Mul(c2, secp256k1B2))
+	k2.Neg(k2)
+
+	if k1.Sign() < 0 {
+		k1Neg = true
+		k1.Neg(k1)
+	}
+	if k2.Sign() < 0 {
+		k2Neg = true
+		k2.Neg(k2)
+	}
+	return
+}
+
+// scalarMultGLV is the secp256k1 fast path for ScalarMult: it splits k into
+// (k1, k2) via splitK and evaluates k1·P + k2·φ(P) with a joint
+// Straus–Shamir ladder instead of a single full-width double-and-add.
+func (curve *Curve) scalarMultGLV(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	kInt := new(big.Int).SetBytes(k)
+	kInt.Mod(kInt, curve.N)
+	if kInt.Sign() == 0 {
+		return nil, nil
+	}
+
+	k1, k2, k1Neg, k2Neg := curve.splitK(kInt)
+
+	p1x, p1y := new(big.Int).Set(Bx), new(big.Int).Set(By)
+	if k1Neg {
+		p1y.Sub(curve.P, p1y)
+	}
+
+	p2x := new(big.Int).Mul(Bx, secp256k1Beta)
+	p2x.Mod(p2x, curve.P)
+	p2y := new(big.Int).Set(By)
+	if k2Neg {
+		p2y.Sub(curve.P, p2y)
+	}
+
+	return curve.shamirCombine(p1x, p1y, k1.Bytes(), p2x, p2y, k2.Bytes())
+}
+
+// shamirCombine computes k1·P1 + k2·P2 with a single interleaved
+// Straus–Shamir double-and-add: the accumulator is doubled once per bit and
+// P1, P2 or their precomputed sum is added depending on the current pair of
+// bits of k1 and k2, walked MSB-first in lockstep. Like ScalarMult and
+// ScalarBaseMult, it reports the point at infinity as (nil, nil) rather than
+// the (0, 0) sentinel it uses internally.
+func (curve *Curve) shamirCombine(p1x, p1y *big.Int, k1 []byte, p2x, p2y *big.Int, k2 []byte) (*big.Int, *big.Int) {
+	sumX, sumY := curve.Add(p1x, p1y, p2x, p2y)
+
+	k1Int := new(big.Int).SetBytes(k1)
+	k2Int := new(big.Int).SetBytes(k2)
+
+	bitLen := k1Int.BitLen()
+	if l := k2Int.BitLen(); l > bitLen {
+		bitLen = l
+	}
+
+	x, y := big.NewInt(0), big.NewInt(0)
+	for i := bitLen - 1; i >= 0; i-- {
+		x, y = curve.doubleOrInfinity(x, y)
+
+		b1 := k1Int.Bit(i)
+		b2 := k2Int.Bit(i)
+		switch {
+		case b1 == 1 && b2 == 1:
+			x, y = curve.Add(x, y, sumX, sumY)
+		case b1 == 1:
+			x, y = curve.Add(x, y, p1x, p1y)
+		case b2 == 1:
+			x, y = curve.Add(x, y, p2x, p2y)
+		}
+	}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, nil
+	}
+	return x, y
+}
+
+// CombinedMult returns baseScalar·G + scalar·(Qx,Qy), which is the
+// computation ECDSA verification needs. On every curve but secp256k1 it
+// shares a single interleaved Straus–Shamir ladder (shamirCombine) between
+// the two scalar multiplications instead of running ScalarBaseMult and
+// ScalarMult independently and Adding the results. On secp256k1 it instead
+// computes baseScalar·G and scalar·Q separately, each with the GLV path
+// (scalarMultGLV), and Adds the two results; the two products do not share
+// a ladder there.
+func (curve *Curve) CombinedMult(Qx, Qy *big.Int, baseScalar, scalar []byte) (x, y *big.Int) {
+	if curve == secp256k1 {
+		gx, gy := curve.scalarMultGLV(curve.Gx, curve.Gy, baseScalar)
+		qx, qy := curve.scalarMultGLV(Qx, Qy, scalar)
+		if gx == nil {
+			return qx, qy
+		}
+		if qx == nil {
+			return gx, gy
+		}
+		x, y = curve.Add(gx, gy, qx, qy)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, nil
+		}
+		return x, y
+	}
+	return curve.shamirCombine(curve.Gx, curve.Gy, baseScalar, Qx, Qy, scalar)
 }
 
 //curve parameters taken from:
@@ -289,6 +721,8 @@ func initS256() {
 	secp256k1.Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
 	secp256k1.Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
 	secp256k1.BitSize = 256
+
+	initS256GLV()
 }
 
 // S160 returns a Curve which implements secp160k1 (see SEC 2 section 2.4.1)
@@ -315,69 +749,176 @@ func S256() *Curve {
 	return secp256k1
 }
 
-// Point Compression Routines. These could use a lot of testing.
-func (curve *Curve) CompressPoint(X, Y *big.Int) (cp []byte) {
-	by := new(big.Int).And(Y, big.NewInt(1)).Int64()
-	bx := X.Bytes()
-	cp = make([]byte, len(bx)+1)
-	if by == 1 {
-		cp[0] = byte(3)
-	} else {
-		cp[0] = byte(2)
-	}
-	copy(cp[1:], bx)
+// SEC1 point encoding, as defined in SEC 1, section 2.3.3: uncompressed
+// carries both coordinates, compressed carries only X plus the parity of Y,
+// and hybrid carries both coordinates as well as the (redundant) parity bit.
+const (
+	uncompressedForm byte = 0x04
+	compressedForm0  byte = 0x02
+	compressedForm1  byte = 0x03
+	hybridForm0      byte = 0x06
+	hybridForm1      byte = 0x07
+)
 
-	return
+var (
+	// ErrInvalidPointFormat is returned by Unmarshal when the leading byte
+	// of the encoding is not one of the recognized SEC1 point formats.
+	ErrInvalidPointFormat = errors.New("kelliptic: invalid point format")
+	// ErrInvalidPointLength is returned by Unmarshal when the encoding's
+	// length doesn't match the one implied by its format byte.
+	ErrInvalidPointLength = errors.New("kelliptic: invalid point encoding length")
+	// ErrPointNotOnCurve is returned by Unmarshal when the decoded (or
+	// decompressed) point does not satisfy the curve equation.
+	ErrPointNotOnCurve = errors.New("kelliptic: point is not on the curve")
+	// ErrHybridParityMismatch is returned by Unmarshal when a hybrid
+	// encoding's parity bit doesn't match the decoded Y's least significant bit.
+	ErrHybridParityMismatch = errors.New("kelliptic: hybrid encoding parity does not match y")
+)
+
+// leftPad returns b left-padded with zero bytes to size, so that SEC1's
+// fixed-length field-element encoding is preserved even when b's leading
+// bytes happen to be zero.
+func leftPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
 }
 
-func (curve *Curve) DecompressPoint(cp []byte) (X, Y *big.Int, err error) {
-	var c int64
-
-	switch cp[0] { // c = 2 most signiﬁcant bits of S
-	case byte(0x03):
-		c = 1
-		break
-	case byte(0x02):
-		c = 0
-		break
-	case byte(0x04): // This is an uncompressed point. Use base Unmarshal.
-		X, Y = elliptic.Unmarshal(curve, cp)
-		return
+// Marshal converts the point (x, y) into its SEC1 octet-string encoding.
+// form selects the encoding: uncompressed (0x04), compressed (0x02/0x03,
+// chosen by the parity of y if the wrong one of the pair is given) or
+// hybrid (0x06/0x07, likewise). X and Y are always left-padded to
+// (BitSize+7)/8 bytes. Marshal panics if form is not one of these values.
+func (curve *Curve) Marshal(x, y *big.Int, form byte) []byte {
+	byteLen := (curve.BitSize + 7) / 8
+	xBytes := leftPad(x.Bytes(), byteLen)
+	yParity := byte(y.Bit(0))
+
+	switch form {
+	case uncompressedForm:
+		out := make([]byte, 1+2*byteLen)
+		out[0] = uncompressedForm
+		copy(out[1:1+byteLen], xBytes)
+		copy(out[1+byteLen:], leftPad(y.Bytes(), byteLen))
+		return out
+
+	case compressedForm0, compressedForm1:
+		out := make([]byte, 1+byteLen)
+		out[0] = compressedForm0 + yParity
+		copy(out[1:], xBytes)
+		return out
+
+	case hybridForm0, hybridForm1:
+		out := make([]byte, 1+2*byteLen)
+		out[0] = hybridForm0 + yParity
+		copy(out[1:1+byteLen], xBytes)
+		copy(out[1+byteLen:], leftPad(y.Bytes(), byteLen))
+		return out
+
 	default:
-		return nil, nil, errors.New("Not a compressed point. (Invalid Header)")
+		panic("kelliptic: invalid point format")
 	}
+}
 
-	byteLen := (curve.Params().BitSize + 7) >> 3
-	if len(cp) != 1+byteLen {
-		return nil, nil, errors.New("Not a compressed point. (Require 1 + key size)")
+// Unmarshal parses a SEC1-encoded point in any of the uncompressed,
+// compressed or hybrid forms and verifies that it lies on the curve,
+// returning a typed error for each way the encoding can be invalid.
+func (curve *Curve) Unmarshal(data []byte) (x, y *big.Int, err error) {
+	if len(data) == 0 {
+		return nil, nil, ErrInvalidPointLength
 	}
 
-	X = new(big.Int).SetBytes(cp[1:])
-	Y = new(big.Int)
+	byteLen := (curve.BitSize + 7) / 8
+
+	switch data[0] {
+	case uncompressedForm:
+		if len(data) != 1+2*byteLen {
+			return nil, nil, ErrInvalidPointLength
+		}
+		x = new(big.Int).SetBytes(data[1 : 1+byteLen])
+		y = new(big.Int).SetBytes(data[1+byteLen:])
+
+	case compressedForm0, compressedForm1:
+		if len(data) != 1+byteLen {
+			return nil, nil, ErrInvalidPointLength
+		}
+		x = new(big.Int).SetBytes(data[1:])
+		y, err = curve.decompressY(x, uint(data[0]-compressedForm0))
+		if err != nil {
+			return nil, nil, err
+		}
 
-	Y.Mod(Y.Mul(X, X), curve.P) // solve for y in y**2 = x**3 + x*a + b (mod p)
-	Y.Mod(Y.Mul(Y, X), curve.P) // assume a = 0
-	Y.Mod(Y.Add(Y, curve.B), curve.P)
+	case hybridForm0, hybridForm1:
+		if len(data) != 1+2*byteLen {
+			return nil, nil, ErrInvalidPointLength
+		}
+		x = new(big.Int).SetBytes(data[1 : 1+byteLen])
+		y = new(big.Int).SetBytes(data[1+byteLen:])
+		if uint(data[0]-hybridForm0) != uint(y.Bit(0)) {
+			return nil, nil, ErrHybridParityMismatch
+		}
 
-	Y = curve.Sqrt(Y)
+	default:
+		return nil, nil, ErrInvalidPointFormat
+	}
 
-	if Y.Cmp(big.NewInt(0)) == 0 {
-		return nil, nil, errors.New("Not a compressed point. (Not on curve)")
+	if !curve.IsOnCurve(x, y) {
+		return nil, nil, ErrPointNotOnCurve
 	}
+	return x, y, nil
+}
 
-	if c != new(big.Int).And(Y, big.NewInt(1)).Int64() {
-		Y.Sub(curve.P, Y)
+// decompressY recovers Y from X and the parity of Y (0 or 1), by solving
+// y² = x³ + b (mod p) and picking the root with the requested parity.
+func (curve *Curve) decompressY(x *big.Int, parity uint) (*big.Int, error) {
+	y := new(big.Int).Mul(x, x)
+	y.Mul(y, x)
+	y.Add(y, curve.B)
+	y.Mod(y, curve.P)
+
+	y = curve.Sqrt(y)
+	if y.Sign() == 0 {
+		return nil, ErrPointNotOnCurve
 	}
 
-	return
+	if uint(y.Bit(0)) != parity {
+		y.Sub(curve.P, y)
+	}
+	return y, nil
+}
+
+// CompressPoint compresses a point on the curve into SEC1 compressed form.
+//
+// Deprecated: use Marshal instead, which also supports the uncompressed
+// and hybrid forms.
+func (curve *Curve) CompressPoint(X, Y *big.Int) []byte {
+	form := compressedForm0
+	if Y.Bit(0) == 1 {
+		form = compressedForm1
+	}
+	return curve.Marshal(X, Y, form)
+}
+
+// DecompressPoint decompresses a point encoded by CompressPoint (or any
+// other SEC1 form).
+//
+// Deprecated: use Unmarshal instead.
+func (curve *Curve) DecompressPoint(cp []byte) (X, Y *big.Int, err error) {
+	return curve.Unmarshal(cp)
 }
 
 // Sqrt returns the module square root.
 //
 // Modulo Square root involves deep magic. Uses the Shanks-Tonelli algorithem:
-//    http://en.wikipedia.org/wiki/Shanks-Tonelli_algorithm
+//
+//	http://en.wikipedia.org/wiki/Shanks-Tonelli_algorithm
+//
 // Translated from a python implementation found here:
-//    http://eli.thegreenplace.net/2009/03/07/computing-modular-square-roots-in-python/
+//
+//	http://eli.thegreenplace.net/2009/03/07/computing-modular-square-roots-in-python/
 func (curve *Curve) Sqrt(a *big.Int) *big.Int {
 	ZERO := big.NewInt(0)
 	ONE := big.NewInt(1)
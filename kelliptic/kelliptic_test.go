@@ -0,0 +1,359 @@
+// Copyright 2011 ThePiachu. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kelliptic
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func testCurves() map[string]*Curve {
+	return map[string]*Curve{
+		"S160": S160(),
+		"S192": S192(),
+		"S224": S224(),
+		"S256": S256(),
+	}
+}
+
+// TestAddInfinity checks that Add treats (0,0) as the point at infinity:
+// inf + P, P + inf and inf + inf.
+func TestAddInfinity(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			inf := big.NewInt(0)
+			px, py := curve.Gx, curve.Gy
+
+			if x, y := curve.Add(inf, inf, px, py); x.Cmp(px) != 0 || y.Cmp(py) != 0 {
+				t.Errorf("Add(inf, P) = (%x, %x), want (%x, %x)", x, y, px, py)
+			}
+
+			if x, y := curve.Add(px, py, inf, inf); x.Cmp(px) != 0 || y.Cmp(py) != 0 {
+				t.Errorf("Add(P, inf) = (%x, %x), want (%x, %x)", x, y, px, py)
+			}
+
+			if x, y := curve.Add(inf, inf, inf, inf); x.Sign() != 0 || y.Sign() != 0 {
+				t.Errorf("Add(inf, inf) = (%x, %x), want (0, 0)", x, y)
+			}
+		})
+	}
+}
+
+// TestAddDoubling checks that Add(P, P) agrees with Double(P).
+func TestAddDoubling(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			px, py := curve.Gx, curve.Gy
+
+			addX, addY := curve.Add(px, py, px, py)
+			dblX, dblY := curve.Double(px, py)
+			if addX.Cmp(dblX) != 0 || addY.Cmp(dblY) != 0 {
+				t.Errorf("Add(P, P) = (%x, %x), Double(P) = (%x, %x)", addX, addY, dblX, dblY)
+			}
+		})
+	}
+}
+
+// TestAddNegation checks that P + (-P) is the point at infinity.
+func TestAddNegation(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			px, py := curve.Gx, curve.Gy
+			negY := new(big.Int).Sub(curve.P, py)
+
+			x, y := curve.Add(px, py, px, negY)
+			if x.Sign() != 0 || y.Sign() != 0 {
+				t.Errorf("Add(P, -P) = (%x, %x), want (0, 0)", x, y)
+			}
+		})
+	}
+}
+
+// TestScalarMultConstTime checks that ScalarMultConstTime agrees with
+// ScalarMult for random scalars on every curve.
+func TestScalarMultConstTime(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			scalars := []*big.Int{big.NewInt(1), big.NewInt(2)}
+			for i := 0; i < 20; i++ {
+				k, err := rand.Int(rand.Reader, curve.N)
+				if err != nil {
+					t.Fatal(err)
+				}
+				scalars = append(scalars, k)
+			}
+
+			for _, k := range scalars {
+				wantX, wantY := curve.ScalarMult(curve.Gx, curve.Gy, k.Bytes())
+				gotX, gotY := curve.ScalarMultConstTime(curve.Gx, curve.Gy, k.Bytes())
+				if gotX.Cmp(wantX) != 0 || gotY.Cmp(wantY) != 0 {
+					t.Fatalf("k=%x: ScalarMultConstTime = (%x, %x), ScalarMult = (%x, %x)", k, gotX, gotY, wantX, wantY)
+				}
+			}
+		})
+	}
+}
+
+// TestScalarBaseMult checks that ScalarBaseMult agrees with
+// ScalarMult(Gx, Gy, k) for random scalars on every curve.
+func TestScalarBaseMult(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			scalars := []*big.Int{big.NewInt(1), big.NewInt(baseWindowWidth)}
+			for i := 0; i < 20; i++ {
+				k, err := rand.Int(rand.Reader, curve.N)
+				if err != nil {
+					t.Fatal(err)
+				}
+				scalars = append(scalars, k)
+			}
+
+			for _, k := range scalars {
+				wantX, wantY := curve.ScalarMult(curve.Gx, curve.Gy, k.Bytes())
+				gotX, gotY := curve.ScalarBaseMult(k.Bytes())
+				if gotX.Cmp(wantX) != 0 || gotY.Cmp(wantY) != 0 {
+					t.Fatalf("k=%x: ScalarBaseMult = (%x, %x), ScalarMult = (%x, %x)", k, gotX, gotY, wantX, wantY)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScalarBaseMult and BenchmarkScalarMultBasePoint compare the
+// windowed base-point table against ScalarMult on the base point (which on
+// S256 takes the GLV path).
+func BenchmarkScalarBaseMult(b *testing.B) {
+	curve := S256()
+	k := make([]byte, 32)
+	rand.Read(k)
+	curve.baseOnce.Do(curve.buildBaseTable) // exclude one-time table build
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.ScalarBaseMult(k)
+	}
+}
+
+func BenchmarkScalarMultBasePoint(b *testing.B) {
+	curve := S256()
+	k := make([]byte, 32)
+	rand.Read(k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMult(curve.Gx, curve.Gy, k)
+	}
+}
+
+// doubleAndAddReference computes k*(Bx,By) with the plain non-GLV
+// double-and-add algorithm, independent of scalarMultGLV, for use as an
+// oracle in TestScalarMultGLV.
+func doubleAndAddReference(curve *Curve, Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	Bz := new(big.Int).SetInt64(1)
+	x, y, z := Bx, By, Bz
+
+	seenFirstTrue := false
+	for _, byte := range k {
+		for bitNum := 0; bitNum < 8; bitNum++ {
+			if seenFirstTrue {
+				x, y, z = curve.doubleJacobian(x, y, z)
+			}
+			if byte&0x80 == 0x80 {
+				if !seenFirstTrue {
+					seenFirstTrue = true
+				} else {
+					x, y, z = curve.addJacobian(Bx, By, Bz, x, y, z)
+				}
+			}
+			byte <<= 1
+		}
+	}
+
+	if !seenFirstTrue {
+		return nil, nil
+	}
+	return curve.affineFromJacobian(x, y, z)
+}
+
+// TestScalarMultGLV checks that secp256k1's GLV-accelerated ScalarMult
+// agrees with the plain double-and-add algorithm on random scalars.
+func TestScalarMultGLV(t *testing.T) {
+	curve := S256()
+	for i := 0; i < 50; i++ {
+		k, err := rand.Int(rand.Reader, curve.N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.Sign() == 0 {
+			continue
+		}
+
+		wantX, wantY := doubleAndAddReference(curve, curve.Gx, curve.Gy, k.Bytes())
+		gotX, gotY := curve.scalarMultGLV(curve.Gx, curve.Gy, k.Bytes())
+		if gotX.Cmp(wantX) != 0 || gotY.Cmp(wantY) != 0 {
+			t.Fatalf("k=%x: scalarMultGLV = (%x, %x), reference = (%x, %x)", k, gotX, gotY, wantX, wantY)
+		}
+	}
+}
+
+// TestCombinedMult checks that CombinedMult agrees with running
+// ScalarBaseMult and ScalarMult separately and Adding the results, on every
+// curve (exercising both the GLV-accelerated secp256k1 path and the generic
+// shamirCombine path).
+func TestCombinedMult(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			baseScalar, err := rand.Int(rand.Reader, curve.N)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scalar, err := rand.Int(rand.Reader, curve.N)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Qx, Qy := curve.ScalarBaseMult(scalar.Bytes())
+
+			gx, gy := curve.ScalarBaseMult(baseScalar.Bytes())
+			qx, qy := curve.ScalarMult(Qx, Qy, scalar.Bytes())
+			wantX, wantY := curve.Add(gx, gy, qx, qy)
+
+			gotX, gotY := curve.CombinedMult(Qx, Qy, baseScalar.Bytes(), scalar.Bytes())
+			if gotX.Cmp(wantX) != 0 || gotY.Cmp(wantY) != 0 {
+				t.Fatalf("CombinedMult = (%x, %x), want (%x, %x)", gotX, gotY, wantX, wantY)
+			}
+		})
+	}
+}
+
+// TestCombinedMultZero checks that CombinedMult reports the point at
+// infinity as (nil, nil), as ScalarMult and ScalarBaseMult do for a zero
+// scalar, on every curve rather than just secp256k1's GLV path.
+func TestCombinedMultZero(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			x, y := curve.CombinedMult(curve.Gx, curve.Gy, []byte{0}, []byte{0})
+			if x != nil || y != nil {
+				t.Fatalf("CombinedMult(G, 0, 0) = (%x, %x), want (nil, nil)", x, y)
+			}
+		})
+	}
+}
+
+// TestCombinedMultCancel checks that CombinedMult also reports the point at
+// infinity as (nil, nil) when it arises from two non-zero scalars cancelling
+// out (baseScalar·G + (N-baseScalar)·G), not just from a zero scalar. On
+// secp256k1 this result comes back from Add rather than from scalarMultGLV's
+// own zero-scalar check, so it needs its own normalization.
+func TestCombinedMultCancel(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			baseScalar := big.NewInt(12345)
+			scalar := new(big.Int).Sub(curve.N, baseScalar)
+			x, y := curve.CombinedMult(curve.Gx, curve.Gy, baseScalar.Bytes(), scalar.Bytes())
+			if x != nil || y != nil {
+				t.Fatalf("CombinedMult(G, 12345, N-12345) = (%x, %x), want (nil, nil)", x, y)
+			}
+		})
+	}
+}
+
+// BenchmarkScalarMultGLV and BenchmarkScalarMultReference compare the
+// GLV-accelerated path against the plain double-and-add algorithm it
+// replaces on secp256k1.
+func BenchmarkScalarMultGLV(b *testing.B) {
+	curve := S256()
+	k := make([]byte, 32)
+	rand.Read(k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		curve.scalarMultGLV(curve.Gx, curve.Gy, k)
+	}
+}
+
+func BenchmarkScalarMultReference(b *testing.B) {
+	curve := S256()
+	k := make([]byte, 32)
+	rand.Read(k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		doubleAndAddReference(curve, curve.Gx, curve.Gy, k)
+	}
+}
+
+// TestMarshalUnmarshalRoundTrip checks that every SEC1 form round-trips
+// through Marshal/Unmarshal on every curve.
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	forms := []byte{uncompressedForm, compressedForm0, hybridForm0}
+
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			for _, form := range forms {
+				data := curve.Marshal(curve.Gx, curve.Gy, form)
+				x, y, err := curve.Unmarshal(data)
+				if err != nil {
+					t.Fatalf("form %#x: Unmarshal: %v", form, err)
+				}
+				if x.Cmp(curve.Gx) != 0 || y.Cmp(curve.Gy) != 0 {
+					t.Fatalf("form %#x: round-trip = (%x, %x), want (%x, %x)", form, x, y, curve.Gx, curve.Gy)
+				}
+			}
+		})
+	}
+}
+
+// TestCompressDecompressPoint checks the deprecated CompressPoint /
+// DecompressPoint pair round-trips on every curve.
+func TestCompressDecompressPoint(t *testing.T) {
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			cp := curve.CompressPoint(curve.Gx, curve.Gy)
+			x, y, err := curve.DecompressPoint(cp)
+			if err != nil {
+				t.Fatalf("DecompressPoint: %v", err)
+			}
+			if x.Cmp(curve.Gx) != 0 || y.Cmp(curve.Gy) != 0 {
+				t.Fatalf("round-trip = (%x, %x), want (%x, %x)", x, y, curve.Gx, curve.Gy)
+			}
+		})
+	}
+}
+
+// TestUnmarshalErrors checks that Unmarshal returns the right typed error
+// for each malformed encoding.
+func TestUnmarshalErrors(t *testing.T) {
+	curve := S256()
+	good := curve.Marshal(curve.Gx, curve.Gy, uncompressedForm)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrInvalidPointLength},
+		{"bad format byte", append([]byte{0x01}, good[1:]...), ErrInvalidPointFormat},
+		{"short uncompressed", good[:len(good)-1], ErrInvalidPointLength},
+		{"short compressed", []byte{compressedForm0}, ErrInvalidPointLength},
+		{"hybrid parity mismatch", append([]byte{hybridForm0 + (1 - byte(curve.Gy.Bit(0)))}, good[1:]...), ErrHybridParityMismatch},
+		{"not on curve", func() []byte {
+			bad := make([]byte, len(good))
+			copy(bad, good)
+			bad[len(bad)-1] ^= 1
+			return bad
+		}(), ErrPointNotOnCurve},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := curve.Unmarshal(tt.data)
+			if err != tt.want {
+				t.Errorf("Unmarshal(%x) = %v, want %v", tt.data, err, tt.want)
+			}
+		})
+	}
+}